@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const chatAPIURL = "https://api.openai.com/v1/chat/completions"
+
+// defaultChatModel é usado quando o usuário não informa um modelo explicitamente.
+const defaultChatModel = "gpt-3.5-turbo"
+
+type ChatMessage struct {
+	Role    string `json:"role"`    // "system", "user" ou "assistant"
+	Content string `json:"content"` // O conteúdo da mensagem
+}
+
+type ChatCompletionRequest struct {
+	Model               string        `json:"model"`                           // O nome do modelo a ser usado para completar o chat
+	Messages            []ChatMessage `json:"messages"`                        // O histórico da conversa, na ordem em que ocorreu
+	Temperature         float64       `json:"temperature,omitempty"`           // O nível de temperatura a ser usado para controlar a aleatoriedade do modelo
+	MaxTokens           int           `json:"max_tokens,omitempty"`            // O número máximo de tokens a serem retornados na resposta (modelos convencionais)
+	MaxCompletionTokens int           `json:"max_completion_tokens,omitempty"` // O número máximo de tokens a serem retornados na resposta (modelos da série o1)
+	Stream              bool          `json:"stream,omitempty"`                // Indica se a resposta deve ser retornada como um stream de dados
+}
+
+type ChatCompletionResponse struct {
+	ID      string       `json:"id"`      // O ID da solicitação de chat completion
+	Model   string       `json:"model"`   // O nome do modelo usado para completar o chat
+	Choices []ChatChoice `json:"choices"` // As opções de resposta geradas pelo modelo
+}
+
+type ChatChoice struct {
+	Index        int         `json:"index"`         // O índice da opção de resposta (começando em 0)
+	Message      ChatMessage `json:"message"`       // A mensagem gerada pelo modelo
+	FinishReason string      `json:"finish_reason"` // A razão pela qual a geração de texto foi interrompida
+}
+
+// generateChat envia o histórico completo de mensagens para o endpoint de chat
+// completions e retorna o conteúdo da resposta do assistente.
+func generateChat(ctx context.Context, messages []ChatMessage, model string, maxTries int) (string, error) {
+	if model == "" {
+		model = defaultChatModel
+	}
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return "", errors.New("OPENAI_API_KEY environment variable not set")
+	}
+
+	temperature := 0.8
+	if isO1Model(model) {
+		temperature = 0
+	}
+	if err := validateChatRequest(messages, model, temperature, false); err != nil {
+		return "", err
+	}
+
+	chatRequest := ChatCompletionRequest{Model: model, Messages: messages}
+	if isO1Model(model) {
+		chatRequest.MaxCompletionTokens = 2000
+	} else {
+		chatRequest.Temperature = temperature
+		chatRequest.MaxTokens = 2000
+	}
+
+	requestBody, err := json.Marshal(chatRequest)
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{}
+	resp, err := doRequestWithRetry(ctx, client, maxTries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", chatAPIURL, bytes.NewBuffer(requestBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var chatResponse ChatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResponse); err != nil {
+		return "", err
+	}
+
+	if len(chatResponse.Choices) == 0 {
+		return "", errors.New("chat completion response contained no choices")
+	}
+
+	return chatResponse.Choices[0].Message.Content, nil
+}
@@ -3,12 +3,16 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"time"
 )
 
 const apiURL = "https://api.openai.com/v1/completions"
@@ -70,7 +74,7 @@ type Choice struct {
 	DiversityScore   float64 `json:"diversity_score"`   // A pontuação de diversidade da opção de compleção
 }
 
-func generateText(prompt string, model string) (string, error) {
+func generateText(ctx context.Context, prompt string, model string, maxTries int) (string, error) {
 	if model == "" {
 		model = "text-davinci-003"
 	}
@@ -91,25 +95,21 @@ func generateText(prompt string, model string) (string, error) {
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
-
 	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := doRequestWithRetry(ctx, client, maxTries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(requestBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+		return req, nil
+	})
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
-	}
-
 	// Deserialize a resposta
 	var completionResponse CompletionResponse
 	err = json.NewDecoder(resp.Body).Decode(&completionResponse)
@@ -121,12 +121,117 @@ func generateText(prompt string, model string) (string, error) {
 }
 
 func main() {
+	legacy := flag.Bool("legacy", false, "use the legacy /v1/completions endpoint instead of chat completions")
+	model := flag.String("model", "", "model to use (defaults to text-davinci-003 for --legacy, gpt-3.5-turbo otherwise)")
+	stream := flag.Bool("stream", false, "stream the response token by token as it arrives")
+	showUsage := flag.Bool("show-usage", false, "print token usage to stderr once the response finishes (requires --stream)")
+	provider := flag.String("provider", "", "backend to use: openai, anthropic or mistral (defaults to $DEFAULT_COMPLETER, then openai)")
+	session := flag.String("session", "", "session management: \"new\", \"list\", \"rm\" (with the id as the next argument), or an existing session id to resume")
+	maxTries := flag.Int("max-tries", 3, "maximum number of attempts per request, retrying on 429 and 5xx responses")
+	timeout := flag.Duration("timeout", 30*time.Second, "per-request timeout")
+	tui := flag.Bool("tui", false, "launch an interactive Bubble Tea chat UI")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *tui {
+		if err := runTUI(ctx, *model, *maxTries, *timeout); err != nil {
+			fmt.Println(err)
+		}
+		return
+	}
+
+	if *session != "" {
+		runSessionCommand(ctx, *session, *model, *stream, *showUsage, *maxTries, *timeout)
+		return
+	}
+
+	if *provider != "" || os.Getenv("DEFAULT_COMPLETER") != "" {
+		runProviderRepl(ctx, *provider, *model, *stream, *showUsage, *timeout)
+		return
+	}
+
+	if *legacy {
+		runLegacyRepl(ctx, *model, *stream, *showUsage, *maxTries, *timeout)
+		return
+	}
+
+	runChatRepl(ctx, *model, *stream, *showUsage, *maxTries, *timeout)
+}
+
+// runProviderRepl conduz um loop de conversa usando o Completer do provedor
+// selecionado. Diferente de runChatRepl, cada turno é enviado de forma
+// independente, sem histórico, já que a interface Completer opera sobre um
+// único prompt por vez.
+func runProviderRepl(parentCtx context.Context, provider string, model string, stream bool, showUsage bool, timeout time.Duration) {
+	completer, err := newCompleter(provider)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	opts := CompleterOptions{Model: model, Temperature: 0.8, MaxTokens: 2000, ShowUsage: showUsage}
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print("You: ")
+		prompt, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		prompt = strings.TrimSpace(prompt)
+		if prompt == "" {
+			continue
+		}
+
+		func() {
+			ctx, cancel := context.WithTimeout(parentCtx, timeout)
+			defer cancel()
+
+			if stream {
+				fmt.Print("Assistant: ")
+				tokens, err := completer.Stream(ctx, prompt, opts)
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+				for token := range tokens {
+					fmt.Print(token.Content)
+				}
+				fmt.Println()
+				return
+			}
+
+			completion, err := completer.Complete(ctx, prompt, opts)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			fmt.Printf("Assistant: %s\n", completion.Content)
+		}()
+	}
+}
+
+// runLegacyRepl mantém o comportamento original: um único prompt, sem histórico.
+func runLegacyRepl(ctx context.Context, model string, stream bool, showUsage bool, maxTries int, timeout time.Duration) {
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Print("Enter prompt: ")
 	prompt, _ := reader.ReadString('\n')
 	prompt = strings.TrimSpace(prompt)
 
-	result, err := generateText(prompt, "")
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if stream {
+		if _, err := generateTextStream(ctx, prompt, model, maxTries, showUsage); err != nil {
+			fmt.Println(err)
+		}
+		fmt.Println()
+		return
+	}
+
+	result, err := generateText(ctx, prompt, model, maxTries)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -134,3 +239,142 @@ func main() {
 
 	fmt.Println(result)
 }
+
+// runSessionCommand lida com o subcomando --session: "list" imprime as
+// sessões existentes, "rm" apaga a sessão cujo id é o próximo argumento
+// posicional, "new" cria uma sessão vazia e entra no REPL, e qualquer outro
+// valor é tratado como o id de uma sessão existente a ser retomada.
+func runSessionCommand(ctx context.Context, cmd string, model string, stream bool, showUsage bool, maxTries int, timeout time.Duration) {
+	switch cmd {
+	case "list":
+		sessions, err := listSessions()
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		for _, s := range sessions {
+			fmt.Printf("%s\t%s\t%s\t%d messages\n", s.ID, s.CreatedAt, s.Model, len(s.Messages))
+		}
+	case "rm":
+		id := flag.Arg(0)
+		if id == "" {
+			fmt.Println("usage: --session rm <id>")
+			return
+		}
+		if err := removeSession(id); err != nil {
+			fmt.Println(err)
+		}
+	case "new":
+		s, err := newSession(model)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Started session %s\n", s.ID)
+		runSessionRepl(ctx, s, stream, showUsage, maxTries, timeout)
+	default:
+		s, err := loadSession(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if model != "" {
+			s.Model = model
+		}
+		runSessionRepl(ctx, s, stream, showUsage, maxTries, timeout)
+	}
+}
+
+// runSessionRepl é o equivalente de runChatRepl para conversas que persistem
+// em disco: cada turno é gravado na sessão assim que a resposta chega, e o
+// histórico é podado para caber no orçamento de tokens configurado.
+func runSessionRepl(parentCtx context.Context, s *Session, stream bool, showUsage bool, maxTries int, timeout time.Duration) {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print("You: ")
+		prompt, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		prompt = strings.TrimSpace(prompt)
+		if prompt == "" {
+			continue
+		}
+
+		s.Messages = append(s.Messages, ChatMessage{Role: "user", Content: prompt})
+		s.Messages = trimToTokenBudget(s.Messages, defaultTokenBudget)
+
+		ctx, cancel := context.WithTimeout(parentCtx, timeout)
+
+		var reply string
+		if stream {
+			fmt.Print("Assistant: ")
+			reply, err = generateChatStream(ctx, s.Messages, s.Model, maxTries, showUsage)
+			fmt.Println()
+		} else {
+			reply, err = generateChat(ctx, s.Messages, s.Model, maxTries)
+		}
+		cancel()
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if !stream {
+			fmt.Printf("Assistant: %s\n", reply)
+		}
+
+		s.Messages = append(s.Messages, ChatMessage{Role: "assistant", Content: reply})
+		s.Messages = trimToTokenBudget(s.Messages, defaultTokenBudget)
+
+		if err := saveSession(s); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// runChatRepl conduz um loop de conversa, mantendo o histórico de mensagens em
+// memória para que os próximos turnos tenham contexto dos anteriores.
+func runChatRepl(parentCtx context.Context, model string, stream bool, showUsage bool, maxTries int, timeout time.Duration) {
+	reader := bufio.NewReader(os.Stdin)
+	var messages []ChatMessage
+
+	for {
+		fmt.Print("You: ")
+		prompt, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		prompt = strings.TrimSpace(prompt)
+		if prompt == "" {
+			continue
+		}
+
+		messages = append(messages, ChatMessage{Role: "user", Content: prompt})
+
+		if stream {
+			fmt.Print("Assistant: ")
+			ctx, cancel := context.WithTimeout(parentCtx, timeout)
+			reply, err := generateChatStream(ctx, messages, model, maxTries, showUsage)
+			cancel()
+			fmt.Println()
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			messages = append(messages, ChatMessage{Role: "assistant", Content: reply})
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(parentCtx, timeout)
+		reply, err := generateChat(ctx, messages, model, maxTries)
+		cancel()
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+
+		messages = append(messages, ChatMessage{Role: "assistant", Content: reply})
+		fmt.Printf("Assistant: %s\n", reply)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedParam indica que um parâmetro da requisição não é suportado
+// pelo modelo de destino — por exemplo, os modelos da série o1 rejeitam
+// `temperature` e mensagens com role "system".
+type ErrUnsupportedParam struct {
+	Field string
+}
+
+func (e *ErrUnsupportedParam) Error() string {
+	return fmt.Sprintf("parameter %q is not supported by this model", e.Field)
+}
+
+// isO1Model reporta se model pertence à série de modelos de raciocínio o1,
+// que tem um conjunto de parâmetros suportados diferente dos modelos de chat
+// convencionais.
+func isO1Model(model string) bool {
+	return strings.HasPrefix(model, "o1")
+}
+
+// validateChatRequest garante que messages e temperature são compatíveis com
+// o model de destino, retornando ErrUnsupportedParam para o primeiro
+// parâmetro não suportado encontrado. Para modelos que não são da série o1
+// não há restrições.
+func validateChatRequest(messages []ChatMessage, model string, temperature float64, stream bool) error {
+	if !isO1Model(model) {
+		return nil
+	}
+
+	for _, m := range messages {
+		if m.Role == "system" {
+			return &ErrUnsupportedParam{Field: "system role message"}
+		}
+	}
+	if temperature != 0 {
+		return &ErrUnsupportedParam{Field: "temperature"}
+	}
+	if stream {
+		return &ErrUnsupportedParam{Field: "stream"}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Token é um fragmento de texto emitido por um Completer durante um stream.
+type Token struct {
+	Content string
+}
+
+// Completion é a resposta normalizada de qualquer provedor, independente do
+// formato específico de cada API.
+type Completion struct {
+	Content string
+	Usage   *Usage
+}
+
+// CompleterOptions agrupa os parâmetros de geração comuns a todos os
+// provedores suportados.
+type CompleterOptions struct {
+	Model       string
+	Temperature float64
+	MaxTokens   int
+	ShowUsage   bool
+}
+
+// Completer é implementado por cada provedor de LLM suportado pela CLI.
+type Completer interface {
+	Complete(ctx context.Context, prompt string, opts CompleterOptions) (Completion, error)
+	Stream(ctx context.Context, prompt string, opts CompleterOptions) (<-chan Token, error)
+}
+
+// newCompleter resolve o provedor indicado (via --provider ou DEFAULT_COMPLETER)
+// para sua implementação de Completer, validando que a variável de ambiente
+// com a API key correspondente está presente.
+func newCompleter(provider string) (Completer, error) {
+	if provider == "" {
+		provider = os.Getenv("DEFAULT_COMPLETER")
+	}
+	if provider == "" {
+		provider = "openai"
+	}
+
+	switch provider {
+	case "openai":
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY environment variable not set")
+		}
+		return &OpenAICompleter{APIKey: apiKey}, nil
+	case "anthropic":
+		apiKey := os.Getenv("ANTHROPIC_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable not set")
+		}
+		return &AnthropicCompleter{APIKey: apiKey}, nil
+	case "mistral":
+		apiKey := os.Getenv("MISTRAL_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("MISTRAL_API_KEY environment variable not set")
+		}
+		return &MistralCompleter{APIKey: apiKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+}
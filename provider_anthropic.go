@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const anthropicAPIURL = "https://api.anthropic.com/v1/messages"
+const anthropicVersion = "2023-06-01"
+
+// defaultAnthropicModel é usado quando nenhum modelo é informado.
+const defaultAnthropicModel = "claude-3-5-sonnet-latest"
+
+type anthropicContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	Messages  []anthropicMessage `json:"messages"`
+	MaxTokens int                `json:"max_tokens"`
+	Stream    bool               `json:"stream,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []anthropicContentBlock `json:"content"`
+}
+
+// AnthropicCompleter implementa Completer usando o endpoint /v1/messages da
+// Anthropic.
+type AnthropicCompleter struct {
+	APIKey string
+}
+
+func (c *AnthropicCompleter) newRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", anthropicAPIURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.APIKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+	return req, nil
+}
+
+func (c *AnthropicCompleter) Complete(ctx context.Context, prompt string, opts CompleterOptions) (Completion, error) {
+	model := opts.Model
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	maxTokens := opts.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 2000
+	}
+
+	requestBody, err := json.Marshal(anthropicRequest{
+		Model:     model,
+		MaxTokens: maxTokens,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: []anthropicContentBlock{{Type: "text", Text: prompt}}},
+		},
+	})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	req, err := c.newRequest(ctx, requestBody)
+	if err != nil {
+		return Completion{}, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Completion{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Completion{}, fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+	}
+
+	var parsed anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return Completion{}, err
+	}
+	if len(parsed.Content) == 0 {
+		return Completion{}, fmt.Errorf("anthropic response contained no content blocks")
+	}
+
+	return Completion{Content: parsed.Content[0].Text}, nil
+}
+
+func (c *AnthropicCompleter) Stream(ctx context.Context, prompt string, opts CompleterOptions) (<-chan Token, error) {
+	model := opts.Model
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	maxTokens := opts.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 2000
+	}
+
+	requestBody, err := json.Marshal(anthropicRequest{
+		Model:     model,
+		MaxTokens: maxTokens,
+		Stream:    true,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: []anthropicContentBlock{{Type: "text", Text: prompt}}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+	}
+
+	tokens := make(chan Token)
+	go func() {
+		defer close(tokens)
+		defer resp.Body.Close()
+		streamSSE(resp.Body, func(data string) error {
+			var event struct {
+				Type  string `json:"type"`
+				Delta struct {
+					Text string `json:"text"`
+				} `json:"delta"`
+			}
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				return err
+			}
+			if event.Type == "content_block_delta" && event.Delta.Text != "" {
+				tokens <- Token{Content: event.Delta.Text}
+			}
+			return nil
+		})
+	}()
+
+	return tokens, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const mistralAPIURL = "https://api.mistral.ai/v1/chat/completions"
+
+// defaultMistralModel é usado quando nenhum modelo é informado.
+const defaultMistralModel = "mistral-small-latest"
+
+// MistralCompleter implementa Completer usando o endpoint de chat completions
+// da Mistral, que segue o mesmo formato de requisição/resposta da OpenAI.
+type MistralCompleter struct {
+	APIKey string
+}
+
+func (c *MistralCompleter) newRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", mistralAPIURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+	return req, nil
+}
+
+func (c *MistralCompleter) Complete(ctx context.Context, prompt string, opts CompleterOptions) (Completion, error) {
+	model := opts.Model
+	if model == "" {
+		model = defaultMistralModel
+	}
+
+	requestBody, err := json.Marshal(ChatCompletionRequest{
+		Model:       model,
+		Messages:    []ChatMessage{{Role: "user", Content: prompt}},
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+	})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	req, err := c.newRequest(ctx, requestBody)
+	if err != nil {
+		return Completion{}, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Completion{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Completion{}, fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+	}
+
+	var chatResponse ChatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResponse); err != nil {
+		return Completion{}, err
+	}
+	if len(chatResponse.Choices) == 0 {
+		return Completion{}, fmt.Errorf("chat completion response contained no choices")
+	}
+
+	return Completion{Content: chatResponse.Choices[0].Message.Content}, nil
+}
+
+func (c *MistralCompleter) Stream(ctx context.Context, prompt string, opts CompleterOptions) (<-chan Token, error) {
+	model := opts.Model
+	if model == "" {
+		model = defaultMistralModel
+	}
+
+	reqBody := map[string]interface{}{
+		"model":    model,
+		"messages": []ChatMessage{{Role: "user", Content: prompt}},
+		"stream":   true,
+	}
+
+	requestBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+	}
+
+	tokens := make(chan Token)
+	go func() {
+		defer close(tokens)
+		defer resp.Body.Close()
+		streamSSE(resp.Body, func(data string) error {
+			var chunk ChatCompletionStreamResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				return err
+			}
+			if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+				tokens <- Token{Content: chunk.Choices[0].Delta.Content}
+			}
+			return nil
+		})
+	}()
+
+	return tokens, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// OpenAICompleter implementa Completer usando o endpoint de chat completions
+// da OpenAI.
+type OpenAICompleter struct {
+	APIKey string
+}
+
+func (c *OpenAICompleter) Complete(ctx context.Context, prompt string, opts CompleterOptions) (Completion, error) {
+	model := opts.Model
+	if model == "" {
+		model = defaultChatModel
+	}
+
+	requestBody, err := json.Marshal(ChatCompletionRequest{
+		Model:       model,
+		Messages:    []ChatMessage{{Role: "user", Content: prompt}},
+		Temperature: opts.Temperature,
+		MaxTokens:   opts.MaxTokens,
+	})
+	if err != nil {
+		return Completion{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", chatAPIURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return Completion{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Completion{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Completion{}, fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+	}
+
+	var chatResponse ChatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResponse); err != nil {
+		return Completion{}, err
+	}
+	if len(chatResponse.Choices) == 0 {
+		return Completion{}, fmt.Errorf("chat completion response contained no choices")
+	}
+
+	return Completion{Content: chatResponse.Choices[0].Message.Content}, nil
+}
+
+func (c *OpenAICompleter) Stream(ctx context.Context, prompt string, opts CompleterOptions) (<-chan Token, error) {
+	model := opts.Model
+	if model == "" {
+		model = defaultChatModel
+	}
+
+	reqBody := map[string]interface{}{
+		"model":    model,
+		"messages": []ChatMessage{{Role: "user", Content: prompt}},
+		"stream":   true,
+	}
+	if opts.ShowUsage {
+		reqBody["stream_options"] = StreamOptions{IncludeUsage: true}
+	}
+
+	requestBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", chatAPIURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+	}
+
+	tokens := make(chan Token)
+	go func() {
+		defer close(tokens)
+		defer resp.Body.Close()
+		streamSSE(resp.Body, func(data string) error {
+			var chunk ChatCompletionStreamResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				return err
+			}
+			if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+				tokens <- Token{Content: chunk.Choices[0].Delta.Content}
+			}
+			return nil
+		})
+	}()
+
+	return tokens, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// APIError representa o envelope de erro que a API da OpenAI retorna para
+// respostas não-200: {"error": {"message", "type", "code"}}.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Type       string
+	Code       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error (status %d, type %q): %s", e.StatusCode, e.Type, e.Message)
+}
+
+// parseAPIError decodifica o corpo de uma resposta de erro no formato da
+// OpenAI. Se o corpo não estiver nesse formato, o texto bruto é usado como
+// mensagem.
+func parseAPIError(statusCode int, body []byte) *APIError {
+	var envelope struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error.Message == "" {
+		return &APIError{StatusCode: statusCode, Message: string(body)}
+	}
+	return &APIError{StatusCode: statusCode, Message: envelope.Error.Message, Type: envelope.Error.Type, Code: envelope.Error.Code}
+}
+
+// isRetryableStatus reporta se um código de status HTTP deve ser retentado:
+// 429 (rate limit) e qualquer 5xx. 4xx além de 429 nunca são retentados.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// backoffDelay calcula o atraso antes da próxima tentativa usando backoff
+// exponencial com full jitter: base de 500ms, dobrando a cada tentativa, com
+// teto de 30s.
+func backoffDelay(attempt int) time.Duration {
+	const base = 500 * time.Millisecond
+	const maxDelay = 30 * time.Second
+
+	d := base * time.Duration(math.Pow(2, float64(attempt)))
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// retryAfterDelay lê o cabeçalho Retry-After (em segundos) de uma resposta,
+// quando presente, sobrepondo o backoff calculado.
+func retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	raw := resp.Header.Get("Retry-After")
+	if raw == "" {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// doRequestWithRetry executa a requisição construída por newReq, repetindo em
+// caso de 429/5xx até maxTries tentativas no total. Erros 400/401/403/404
+// (e qualquer outro 4xx não-429) retornam imediatamente, sem retentar. ctx
+// cancela tentativas em andamento e a espera entre elas.
+func doRequestWithRetry(ctx context.Context, client *http.Client, maxTries int, newReq func() (*http.Request, error)) (*http.Response, error) {
+	if maxTries <= 0 {
+		maxTries = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxTries; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+		} else if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		} else {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			apiErr := parseAPIError(resp.StatusCode, body)
+			lastErr = apiErr
+
+			if !isRetryableStatus(resp.StatusCode) {
+				return nil, apiErr
+			}
+
+			delay := backoffDelay(attempt)
+			if override, ok := retryAfterDelay(resp); ok {
+				delay = override
+			}
+			if waitErr := sleepOrCancel(ctx, delay); waitErr != nil {
+				return nil, waitErr
+			}
+			continue
+		}
+
+		if attempt < maxTries-1 {
+			if waitErr := sleepOrCancel(ctx, backoffDelay(attempt)); waitErr != nil {
+				return nil, waitErr
+			}
+		}
+	}
+
+	return nil, lastErr
+}
+
+// sleepOrCancel aguarda d, retornando mais cedo com o erro de ctx caso ele
+// seja cancelado antes disso.
+func sleepOrCancel(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
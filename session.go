@@ -0,0 +1,198 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Session é uma conversa persistida em disco, identificada por um UUID, que
+// pode ser retomada entre invocações da CLI.
+type Session struct {
+	ID        string        `json:"id"`
+	CreatedAt string        `json:"created_at"` // RFC 3339
+	Model     string        `json:"model"`
+	Messages  []ChatMessage `json:"messages"`
+}
+
+// defaultTokenBudget é o limite aproximado de tokens mantido no histórico de
+// uma sessão antes que os turnos mais antigos sejam descartados.
+const defaultTokenBudget = 4000
+
+// sessionsDir resolve o diretório onde as sessões são armazenadas, seguindo a
+// especificação XDG: $XDG_DATA_HOME/go_gpt3_cli/sessions, com fallback para
+// ~/.local/share/go_gpt3_cli/sessions quando a variável não está definida.
+func sessionsDir() (string, error) {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(base, "go_gpt3_cli", "sessions"), nil
+}
+
+func sessionPath(id string) (string, error) {
+	dir, err := sessionsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, id+".json"), nil
+}
+
+// newSessionID gera um UUID v4 sem depender de bibliotecas externas.
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// newSession cria uma sessão vazia e a grava em disco.
+func newSession(model string) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{
+		ID:        id,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Model:     model,
+	}
+	if err := saveSession(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// loadSession lê uma sessão existente pelo ID.
+func loadSession(id string) (*Session, error) {
+	path, err := sessionPath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// saveSession grava a sessão em disco, criando o diretório de sessões se
+// necessário.
+func saveSession(s *Session) error {
+	dir, err := sessionsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	path, err := sessionPath(s.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// removeSession apaga uma sessão do disco.
+func removeSession(id string) error {
+	path, err := sessionPath(id)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// listSessions retorna todas as sessões armazenadas, ordenadas por data de
+// criação.
+func listSessions() ([]Session, error) {
+	dir, err := sessionsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		id := entry.Name()
+		id = id[:len(id)-len(filepath.Ext(id))]
+		s, err := loadSession(id)
+		if err != nil {
+			continue
+		}
+		sessions = append(sessions, *s)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].CreatedAt < sessions[j].CreatedAt })
+	return sessions, nil
+}
+
+// estimateTokens aproxima a contagem de tokens de uma string usando a
+// heurística comum de ~4 caracteres por token.
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// trimToTokenBudget descarta os turnos mais antigos de messages até que a
+// soma estimada de tokens caiba dentro de budget. Mensagens de sistema (a
+// primeira, se houver) são preservadas.
+func trimToTokenBudget(messages []ChatMessage, budget int) []ChatMessage {
+	hasSystem := len(messages) > 0 && messages[0].Role == "system"
+	start := 0
+	if hasSystem {
+		start = 1
+	}
+
+	total := 0
+	for _, m := range messages {
+		total += estimateTokens(m.Content)
+	}
+
+	for total > budget && start < len(messages) {
+		total -= estimateTokens(messages[start].Content)
+		start++
+	}
+
+	if hasSystem && start > 0 {
+		trimmed := make([]ChatMessage, 0, len(messages)-start+1)
+		trimmed = append(trimmed, messages[0])
+		trimmed = append(trimmed, messages[start:]...)
+		return trimmed
+	}
+
+	return messages[start:]
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ssePrefix é o prefixo que o servidor antepõe a cada evento SSE.
+const ssePrefix = "data: "
+
+// sseDone é o marcador enviado pela API para indicar o fim do stream.
+const sseDone = "[DONE]"
+
+// StreamOptions controla o que a API deve incluir nos chunks de um stream.
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // Inclui a contagem de tokens no chunk final do stream
+}
+
+// Usage reporta o consumo de tokens de uma requisição.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ChatCompletionStreamResponse representa um único chunk de um stream de chat.
+type ChatCompletionStreamResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"` // Presente apenas no último chunk quando stream_options.include_usage é true
+}
+
+// CompletionStreamResponse representa um único chunk de um stream do endpoint
+// legado de completions.
+type CompletionStreamResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Text         string `json:"text"`
+		Index        int    `json:"index"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+}
+
+// streamSSE lê o corpo da resposta linha a linha, descarta linhas vazias e de
+// comentário, remove o prefixo "data: " e invoca onChunk para cada evento até
+// encontrar o marcador "[DONE]" ou o fim do stream.
+func streamSSE(body io.Reader, onChunk func(data string) error) error {
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, ssePrefix) {
+			continue
+		}
+
+		data := strings.TrimPrefix(line, ssePrefix)
+		if data == sseDone {
+			return nil
+		}
+
+		if err := onChunk(data); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// openChatStream valida e envia a requisição de chat com stream habilitado,
+// repetindo em 429/5xx através de doRequestWithRetry, e retorna a resposta
+// para o chamador consumir como SSE.
+func openChatStream(ctx context.Context, messages []ChatMessage, model string, maxTries int, showUsage bool) (*http.Response, error) {
+	if model == "" {
+		model = defaultChatModel
+	}
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("OPENAI_API_KEY environment variable not set")
+	}
+
+	if err := validateChatRequest(messages, model, 0, true); err != nil {
+		return nil, err
+	}
+
+	reqBody := map[string]interface{}{
+		"model":    model,
+		"messages": messages,
+		"stream":   true,
+	}
+	if showUsage {
+		reqBody["stream_options"] = StreamOptions{IncludeUsage: true}
+	}
+
+	requestBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{}
+	return doRequestWithRetry(ctx, client, maxTries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", chatAPIURL, bytes.NewBuffer(requestBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+		return req, nil
+	})
+}
+
+// generateChatStream envia o histórico de mensagens com stream habilitado e
+// imprime os tokens da resposta em stdout conforme eles chegam. Retorna a
+// resposta completa concatenada, para que ela possa ser adicionada ao
+// histórico da conversa.
+func generateChatStream(ctx context.Context, messages []ChatMessage, model string, maxTries int, showUsage bool) (string, error) {
+	resp, err := openChatStream(ctx, messages, model, maxTries, showUsage)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	err = streamSSE(resp.Body, func(data string) error {
+		var chunk ChatCompletionStreamResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return err
+		}
+		if chunk.Usage != nil && showUsage {
+			fmt.Fprintf(os.Stderr, "usage: prompt=%d completion=%d total=%d\n",
+				chunk.Usage.PromptTokens, chunk.Usage.CompletionTokens, chunk.Usage.TotalTokens)
+		}
+		if len(chunk.Choices) > 0 {
+			token := chunk.Choices[0].Delta.Content
+			fmt.Print(token)
+			full.WriteString(token)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return full.String(), nil
+}
+
+// streamChatTokens é o equivalente de generateChatStream para consumidores
+// que querem lidar com cada fragmento por conta própria (por exemplo, o modo
+// --tui) em vez de tê-los impressos em stdout.
+func streamChatTokens(ctx context.Context, messages []ChatMessage, model string, maxTries int, onToken func(string)) (string, error) {
+	resp, err := openChatStream(ctx, messages, model, maxTries, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	err = streamSSE(resp.Body, func(data string) error {
+		var chunk ChatCompletionStreamResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return err
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			onToken(chunk.Choices[0].Delta.Content)
+			full.WriteString(chunk.Choices[0].Delta.Content)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return full.String(), nil
+}
+
+// generateTextStream é o equivalente de generateChatStream para o endpoint
+// legado de completions.
+func generateTextStream(ctx context.Context, prompt string, model string, maxTries int, showUsage bool) (string, error) {
+	if model == "" {
+		model = "text-davinci-003"
+	}
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return "", errors.New("OPENAI_API_KEY environment variable not set")
+	}
+
+	reqBody := map[string]interface{}{
+		"prompt":      prompt,
+		"model":       model,
+		"temperature": 0.8,
+		"max_tokens":  2000,
+		"stream":      true,
+	}
+	if showUsage {
+		reqBody["stream_options"] = StreamOptions{IncludeUsage: true}
+	}
+
+	requestBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{}
+	resp, err := doRequestWithRetry(ctx, client, maxTries, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(requestBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	err = streamSSE(resp.Body, func(data string) error {
+		var chunk CompletionStreamResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return err
+		}
+		if chunk.Usage != nil && showUsage {
+			fmt.Fprintf(os.Stderr, "usage: prompt=%d completion=%d total=%d\n",
+				chunk.Usage.PromptTokens, chunk.Usage.CompletionTokens, chunk.Usage.TotalTokens)
+		}
+		if len(chunk.Choices) > 0 {
+			token := chunk.Choices[0].Text
+			fmt.Print(token)
+			full.WriteString(token)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return full.String(), nil
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+)
+
+// tokenMsg carrega um único fragmento de texto recebido do stream da API.
+type tokenMsg struct {
+	content string
+}
+
+// streamDoneMsg sinaliza que o stream da resposta atual terminou.
+type streamDoneMsg struct{}
+
+// errMsg carrega um erro ocorrido durante a chamada à API.
+type errMsg struct{ err error }
+
+// chatModel é o estado do programa Bubble Tea usado pelo modo --tui.
+type chatModel struct {
+	viewport viewport.Model
+	input    textinput.Model
+	spinner  spinner.Model
+	renderer *glamour.TermRenderer
+	program  *tea.Program
+
+	messages  []ChatMessage
+	model     string
+	waiting   bool
+	streaming strings.Builder
+
+	ctx      context.Context
+	maxTries int
+	timeout  time.Duration
+}
+
+// newChatModel monta o modelo inicial do TUI para o model de chat informado.
+func newChatModel(ctx context.Context, model string, maxTries int, timeout time.Duration) *chatModel {
+	ti := textinput.New()
+	ti.Placeholder = "Ask something..."
+	ti.Focus()
+
+	vp := viewport.New(80, 20)
+
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+
+	renderer, _ := glamour.NewTermRenderer(glamour.WithAutoStyle())
+
+	return &chatModel{
+		viewport: vp,
+		input:    ti,
+		spinner:  sp,
+		renderer: renderer,
+		model:    model,
+		ctx:      ctx,
+		maxTries: maxTries,
+		timeout:  timeout,
+	}
+}
+
+func (m *chatModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// streamReply envia messages para a API em modo stream, repassando cada
+// fragmento recebido para o viewport através de tokenMsg conforme ele chega
+// (via m.program.Send), em vez de escrever em stdout.
+func (m *chatModel) streamReply(messages []ChatMessage) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		_, err := streamChatTokens(ctx, messages, m.model, m.maxTries, func(token string) {
+			m.program.Send(tokenMsg{content: token})
+		})
+		if err != nil {
+			return errMsg{err}
+		}
+		return streamDoneMsg{}
+	}
+}
+
+// sendPrompt adiciona o prompt do usuário ao histórico e dispara o stream da
+// resposta do assistente.
+func (m *chatModel) sendPrompt(prompt string) tea.Cmd {
+	m.messages = append(m.messages, ChatMessage{Role: "user", Content: prompt})
+	return m.streamReply(m.messages)
+}
+
+// regenerateLast descarta a última resposta do assistente e reenvia o último
+// turno do usuário (acionado por Ctrl-R).
+func (m *chatModel) regenerateLast() tea.Cmd {
+	if len(m.messages) == 0 {
+		return nil
+	}
+	if m.messages[len(m.messages)-1].Role == "assistant" {
+		m.messages = m.messages[:len(m.messages)-1]
+	}
+	if len(m.messages) == 0 || m.messages[len(m.messages)-1].Role != "user" {
+		return nil
+	}
+
+	return m.streamReply(m.messages)
+}
+
+func (m *chatModel) renderHistory() string {
+	var b strings.Builder
+	for _, msg := range m.messages {
+		prefix := "You"
+		if msg.Role == "assistant" {
+			prefix = "Assistant"
+		}
+		content := msg.Content
+		if m.renderer != nil {
+			if rendered, err := m.renderer.Render(content); err == nil {
+				content = rendered
+			}
+		}
+		fmt.Fprintf(&b, "%s:\n%s\n", prefix, content)
+	}
+	if m.streaming.Len() > 0 {
+		fmt.Fprintf(&b, "Assistant:\n%s\n", m.streaming.String())
+	}
+	return b.String()
+}
+
+func (m *chatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "ctrl+l":
+			m.messages = nil
+			m.streaming.Reset()
+			m.viewport.SetContent("")
+			return m, nil
+		case "ctrl+r":
+			m.waiting = true
+			m.streaming.Reset()
+			cmds = append(cmds, m.regenerateLast(), m.spinner.Tick)
+		case "enter":
+			prompt := strings.TrimSpace(m.input.Value())
+			if prompt == "" {
+				break
+			}
+			m.input.SetValue("")
+			m.waiting = true
+			m.streaming.Reset()
+			cmds = append(cmds, m.sendPrompt(prompt), m.spinner.Tick)
+		case "pgup":
+			m.viewport.LineUp(m.viewport.Height)
+		case "pgdown":
+			m.viewport.LineDown(m.viewport.Height)
+		}
+
+	case tokenMsg:
+		m.streaming.WriteString(msg.content)
+		m.viewport.SetContent(m.renderHistory())
+		m.viewport.GotoBottom()
+
+	case streamDoneMsg:
+		m.waiting = false
+		m.messages = append(m.messages, ChatMessage{Role: "assistant", Content: m.streaming.String()})
+		m.streaming.Reset()
+		m.viewport.SetContent(m.renderHistory())
+		m.viewport.GotoBottom()
+
+	case errMsg:
+		m.waiting = false
+		m.streaming.Reset()
+		m.messages = append(m.messages, ChatMessage{Role: "assistant", Content: "error: " + msg.err.Error()})
+		m.viewport.SetContent(m.renderHistory())
+		m.viewport.GotoBottom()
+
+	case tea.WindowSizeMsg:
+		m.viewport.Width = msg.Width
+		m.viewport.Height = msg.Height - 3
+		m.input.Width = msg.Width
+
+	case spinner.TickMsg:
+		if m.waiting {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *chatModel) View() string {
+	status := "> "
+	if m.waiting {
+		status = m.spinner.View() + " "
+	}
+	return fmt.Sprintf("%s\n%s%s\n", m.viewport.View(), status, m.input.View())
+}
+
+// runTUI inicia o modo interativo com Bubble Tea.
+func runTUI(ctx context.Context, model string, maxTries int, timeout time.Duration) error {
+	m := newChatModel(ctx, model, maxTries, timeout)
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	m.program = p
+	_, err := p.Run()
+	return err
+}